@@ -0,0 +1,13 @@
+package convert
+
+import "github.com/pipelined/signal"
+
+// AlacConfig is an alac output configuration.
+type AlacConfig struct {
+	SampleFormat signal.BitDepth
+}
+
+// Format returns AlacFormat.
+func (AlacConfig) Format() Format {
+	return AlacFormat
+}
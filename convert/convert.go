@@ -0,0 +1,71 @@
+// Package convert provides output configurations for the audio formats
+// supported by phono's convert form and the pipelines that back it.
+package convert
+
+import "fmt"
+
+// Format is a file extension that defines the output audio format.
+type Format string
+
+// Supported output formats.
+const (
+	WavFormat  Format = "wav"
+	Mp3Format  Format = "mp3"
+	FlacFormat Format = "flac"
+	AlacFormat Format = "alac"
+)
+
+// OutputConfig is a configuration of output data, specific to the format.
+// It's implemented by every per-format config (WavConfig, Mp3Config, ...)
+// and used to dispatch the pipeline that produces the converted output.
+type OutputConfig interface {
+	Format() Format
+}
+
+// LosslessFormat describes a lossless output format offered by the convert
+// form, on top of the built-in WAV support.
+type LosslessFormat struct {
+	Format Format
+	Name   string
+}
+
+// LosslessFormats lists the lossless codecs the convert form offers in
+// addition to WAV; the template package builds its output format choices
+// and accepted extensions from it. An entry here alone does not make a
+// codec convertible: the template package's Parse dispatches on a fixed
+// switch of formats, and Run only transcodes formats it has a case for.
+var LosslessFormats = []LosslessFormat{
+	{Format: FlacFormat, Name: "FLAC"},
+	{Format: AlacFormat, Name: "ALAC"},
+}
+
+// unwiredFormats lists formats that can be selected and parsed, but whose
+// pipeline isn't wired up yet. Keep this in sync with Run: it exists so
+// every entry point (the HTTP form, convertgrpc, presets) can reject them
+// immediately after parsing, instead of failing deep inside Run once the
+// upload has already been streamed in.
+var unwiredFormats = map[Format]bool{
+	Mp3Format:  true,
+	FlacFormat: true,
+	AlacFormat: true,
+}
+
+// IsSupported reports whether format's pipeline is wired up in Run. The
+// convert form uses it to decide which formats to offer at all, instead
+// of presenting a format that will always dead-end at CheckSupported.
+func IsSupported(format Format) bool {
+	return !unwiredFormats[format]
+}
+
+// CheckSupported reports an error if cfg's pipeline isn't wired up yet.
+// Call it right after parsing an OutputConfig from user input, so
+// unsupported formats fail fast at the edge rather than deep inside Run.
+func CheckSupported(cfg OutputConfig) error {
+	if cfg == nil {
+		return fmt.Errorf("convert: no output config")
+	}
+	if !IsSupported(cfg.Format()) {
+		return fmt.Errorf("convert: %s encoding is not implemented yet", cfg.Format())
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package convert
+
+// FlacConfig is a flac output configuration.
+type FlacConfig struct {
+	CompressionLevel int
+	BlockSize        int
+}
+
+// Format returns FlacFormat.
+func (FlacConfig) Format() Format {
+	return FlacFormat
+}
@@ -0,0 +1,110 @@
+package convert
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// JobStatus is the lifecycle state of an asynchronous conversion job.
+type JobStatus string
+
+// Job lifecycle states.
+const (
+	JobPending JobStatus = "pending"
+	JobRunning JobStatus = "running"
+	JobDone    JobStatus = "done"
+	JobFailed  JobStatus = "failed"
+)
+
+// Job tracks an asynchronous conversion enqueued through the convert form.
+type Job struct {
+	ID          string
+	Status      JobStatus
+	Progress    float64
+	ErrorString string
+
+	mu     sync.Mutex
+	output []byte
+}
+
+// Output returns the job's converted bytes. It's only populated once
+// Status is JobDone.
+func (j *Job) Output() []byte {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.output
+}
+
+// snapshot returns a copy of the job's current state, safe to read
+// without holding the job's lock.
+func (j *Job) snapshot() Job {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return Job{ID: j.ID, Status: j.Status, Progress: j.Progress, ErrorString: j.ErrorString}
+}
+
+func (j *Job) reportProgress(step int, stepName string, progress float64) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Progress = progress
+}
+
+func (j *Job) finish(output []byte, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if err != nil {
+		j.Status = JobFailed
+		j.ErrorString = err.Error()
+		return
+	}
+	j.Status = JobDone
+	j.Progress = 1
+	j.output = output
+}
+
+// Jobs is the in-memory registry of jobs started through Enqueue.
+var Jobs = &jobRegistry{jobs: make(map[string]*Job)}
+
+// jobRegistry tracks jobs enqueued for background conversion.
+type jobRegistry struct {
+	mu   sync.Mutex
+	next int
+	jobs map[string]*Job
+}
+
+// Enqueue starts converting r into cfg's output format in the background
+// and returns the Job tracking its progress. The pipeline reports
+// progress back onto the job as it moves through its steps.
+func (reg *jobRegistry) Enqueue(r io.Reader, cfg OutputConfig, trim TrimConfig) *Job {
+	reg.mu.Lock()
+	reg.next++
+	job := &Job{ID: fmt.Sprintf("job-%d", reg.next), Status: JobPending}
+	reg.jobs[job.ID] = job
+	reg.mu.Unlock()
+
+	go func() {
+		job.mu.Lock()
+		job.Status = JobRunning
+		job.mu.Unlock()
+
+		if closer, ok := r.(io.Closer); ok {
+			defer closer.Close()
+		}
+
+		var buf bytes.Buffer
+		err := Run(r, &buf, cfg, trim, job.reportProgress)
+		job.finish(buf.Bytes(), err)
+	}()
+
+	return job
+}
+
+// Get returns the job registered under id, or false if none exists.
+func (reg *jobRegistry) Get(id string) (*Job, bool) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	job, ok := reg.jobs[id]
+	return job, ok
+}
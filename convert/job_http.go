@@ -0,0 +1,55 @@
+package convert
+
+import (
+	"encoding/json"
+	"net/http"
+	"path"
+)
+
+// jobStatusResponse is the JSON body served by JobStatusHandler.
+type jobStatusResponse struct {
+	ID          string    `json:"id"`
+	Status      JobStatus `json:"status"`
+	Progress    float64   `json:"progress"`
+	ErrorString string    `json:"error,omitempty"`
+}
+
+// JobStatusHandler serves GET /jobs/{id}: the current status and progress
+// of a job enqueued through Jobs.Enqueue.
+func JobStatusHandler(w http.ResponseWriter, r *http.Request) {
+	job, ok := Jobs.Get(path.Base(r.URL.Path))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	s := job.snapshot()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(jobStatusResponse{
+		ID:          s.ID,
+		Status:      s.Status,
+		Progress:    s.Progress,
+		ErrorString: s.ErrorString,
+	})
+}
+
+// JobDownloadHandler serves GET /jobs/{id}/download: the converted output
+// of a finished job.
+func JobDownloadHandler(w http.ResponseWriter, r *http.Request) {
+	job, ok := Jobs.Get(path.Base(path.Dir(r.URL.Path)))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	s := job.snapshot()
+	switch s.Status {
+	case JobDone:
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write(job.Output())
+	case JobFailed:
+		http.Error(w, s.ErrorString, http.StatusInternalServerError)
+	default:
+		http.Error(w, "job is still "+string(s.Status), http.StatusAccepted)
+	}
+}
@@ -0,0 +1,16 @@
+package convert
+
+import "github.com/pipelined/mp3"
+
+// Mp3Config is a mp3 output configuration.
+type Mp3Config struct {
+	BitRateMode mp3.BitRateMode
+	ChannelMode mp3.ChannelMode
+	BitRate     int
+	VBRQuality  mp3.VBRQuality
+}
+
+// Format returns Mp3Format.
+func (Mp3Config) Format() Format {
+	return Mp3Format
+}
@@ -0,0 +1,164 @@
+package convert
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+)
+
+// wavHeader is the subset of a WAV file's RIFF/fmt/data chunks needed to
+// read its raw PCM samples.
+type wavHeader struct {
+	SampleRate int
+	Channels   int
+	BitDepth   int
+	DataSize   int
+}
+
+// readWavHeader reads the RIFF/fmt chunks off r and stops right after the
+// "data" chunk header, leaving r positioned at the start of the raw PCM
+// samples.
+func readWavHeader(r io.Reader) (wavHeader, error) {
+	var riff [12]byte
+	if _, err := io.ReadFull(r, riff[:]); err != nil {
+		return wavHeader{}, err
+	}
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return wavHeader{}, fmt.Errorf("convert: not a wav file")
+	}
+
+	var h wavHeader
+	for {
+		var chunk [8]byte
+		if _, err := io.ReadFull(r, chunk[:]); err != nil {
+			return wavHeader{}, err
+		}
+		id := string(chunk[0:4])
+		size := int(binary.LittleEndian.Uint32(chunk[4:8]))
+
+		switch id {
+		case "fmt ":
+			body := make([]byte, size)
+			if _, err := io.ReadFull(r, body); err != nil {
+				return wavHeader{}, err
+			}
+			h.Channels = int(binary.LittleEndian.Uint16(body[2:4]))
+			h.SampleRate = int(binary.LittleEndian.Uint32(body[4:8]))
+			h.BitDepth = int(binary.LittleEndian.Uint16(body[14:16]))
+		case "data":
+			h.DataSize = size
+			return h, nil
+		default:
+			if _, err := io.CopyN(ioutil.Discard, r, int64(size)); err != nil {
+				return wavHeader{}, err
+			}
+		}
+	}
+}
+
+// Info reports the sample rate, channel count and total frame count of
+// audio read from r, encoded as format. Only WavFormat is supported
+// today; other formats return an error until their decoders land.
+func Info(r io.Reader, format Format) (sampleRate, channels, frames int, err error) {
+	if format != WavFormat {
+		return 0, 0, 0, fmt.Errorf("convert: peaks for %s is not implemented yet", format)
+	}
+
+	h, err := readWavHeader(r)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	if h.BitDepth != 16 {
+		return 0, 0, 0, fmt.Errorf("convert: peaks only supports 16 bit wav, got %d bit", h.BitDepth)
+	}
+
+	return h.SampleRate, h.Channels, h.DataSize / (h.Channels * 2), nil
+}
+
+// Peaks decodes audio read from r (encoded as format) and computes the
+// interleaved min/max PCM peak per channel, across buckets evenly spaced
+// windows over its full duration: peaks[0], peaks[1] is channel 0's first
+// bucket min/max, peaks[2], peaks[3] is channel 1's, and so on. Only
+// WavFormat is supported today; other formats return an error until
+// their decoders land.
+func Peaks(r io.Reader, format Format, buckets int) ([]int16, error) {
+	if format != WavFormat {
+		return nil, fmt.Errorf("convert: peaks for %s is not implemented yet", format)
+	}
+	if buckets <= 0 {
+		return nil, fmt.Errorf("convert: buckets must be positive")
+	}
+
+	h, err := readWavHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	if h.BitDepth != 16 {
+		return nil, fmt.Errorf("convert: peaks only supports 16 bit wav, got %d bit", h.BitDepth)
+	}
+
+	frames := h.DataSize / (h.Channels * 2)
+	if frames == 0 {
+		return nil, fmt.Errorf("convert: nothing to compute peaks for")
+	}
+
+	bucketFrames := frames / buckets
+	if bucketFrames == 0 {
+		bucketFrames = 1
+	}
+
+	peaks := make([]int16, buckets*h.Channels*2)
+	sample := make([]byte, 2)
+	for b := 0; b < buckets; b++ {
+		mins := make([]int16, h.Channels)
+		maxs := make([]int16, h.Channels)
+		for c := range mins {
+			mins[c] = math.MaxInt16
+			maxs[c] = math.MinInt16
+		}
+
+		start := b * bucketFrames
+		end := start + bucketFrames
+		if b == buckets-1 {
+			end = frames
+		}
+
+		sampled := false
+	frame:
+		for f := start; f < end; f++ {
+			for c := 0; c < h.Channels; c++ {
+				if _, err := io.ReadFull(r, sample); err != nil {
+					if err == io.EOF || err == io.ErrUnexpectedEOF {
+						break frame
+					}
+					return nil, err
+				}
+				sampled = true
+				v := int16(binary.LittleEndian.Uint16(sample))
+				if v < mins[c] {
+					mins[c] = v
+				}
+				if v > maxs[c] {
+					maxs[c] = v
+				}
+			}
+		}
+
+		if !sampled {
+			// No frames landed in this bucket (frames < buckets, or the
+			// file ended mid-bucket): leave it at the zeroed peaks slot
+			// rather than the min/max sentinels, which would otherwise
+			// render as a full-scale inverted spike.
+			continue
+		}
+
+		for c := 0; c < h.Channels; c++ {
+			peaks[(b*h.Channels+c)*2] = mins[c]
+			peaks[(b*h.Channels+c)*2+1] = maxs[c]
+		}
+	}
+
+	return peaks, nil
+}
@@ -0,0 +1,70 @@
+package convert
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// peaksResponse is the JSON body served by PeaksHandler.
+type peaksResponse struct {
+	SampleRate int     `json:"sample_rate"`
+	Channels   int     `json:"channels"`
+	Frames     int     `json:"frames"`
+	Peaks      []int16 `json:"peaks"`
+}
+
+// defaultPeaksBuckets is used when the request doesn't specify a bucket count.
+const defaultPeaksBuckets = 500
+
+// PeaksHandler serves POST /peaks: it accepts the uploaded file under the
+// "input-file" field plus its "format", computes its waveform peaks and
+// responds with them as JSON for the convert form's preview canvas.
+func PeaksHandler(w http.ResponseWriter, r *http.Request) {
+	file, _, err := r.FormFile("input-file")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	format := Format(r.FormValue("format"))
+
+	buckets := defaultPeaksBuckets
+	if v := r.FormValue("buckets"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			buckets = n
+		}
+	}
+
+	seeker, ok := file.(io.Seeker)
+	if !ok {
+		http.Error(w, "convert: uploaded file is not seekable", http.StatusInternalServerError)
+		return
+	}
+
+	sampleRate, channels, frames, err := Info(file, format)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	peaks, err := Peaks(file, format, buckets)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(peaksResponse{
+		SampleRate: sampleRate,
+		Channels:   channels,
+		Frames:     frames,
+		Peaks:      peaks,
+	})
+}
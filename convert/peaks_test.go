@@ -0,0 +1,42 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func writeTestWav(t *testing.T, sampleRate, channels int, samples []int16) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := writeWavHeader(&buf, sampleRate, channels, 16, len(samples)/channels); err != nil {
+		t.Fatalf("writeWavHeader: %v", err)
+	}
+	for _, s := range samples {
+		var b [2]byte
+		binary.LittleEndian.PutUint16(b[:], uint16(s))
+		buf.Write(b[:])
+	}
+	return buf.Bytes()
+}
+
+func TestPeaksEmptyBucketsAreSilent(t *testing.T) {
+	// Fewer frames than requested buckets: some buckets get no samples at
+	// all and must read back as silence, not the min/max sentinels.
+	data := writeTestWav(t, 8000, 1, []int16{100, -100})
+
+	peaks, err := Peaks(bytes.NewReader(data), WavFormat, 5)
+	if err != nil {
+		t.Fatalf("Peaks: %v", err)
+	}
+
+	want := []int16{100, 100, -100, -100, 0, 0, 0, 0, 0, 0}
+	if len(peaks) != len(want) {
+		t.Fatalf("got %d peaks, want %d", len(peaks), len(want))
+	}
+	for i := range want {
+		if peaks[i] != want[i] {
+			t.Errorf("peaks[%d] = %d, want %d", i, peaks[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package convert
+
+import (
+	"fmt"
+	"io"
+)
+
+// ProgressCallback reports progress of a running pipeline: which step is
+// executing (decode, resample, encode, mux...), its name, and how far
+// through that step the pipeline is, from 0 to 1.
+type ProgressCallback func(step int, stepName string, progress float64)
+
+// pipelineSteps names the stages Run reports progress for.
+var pipelineSteps = []string{"decode", "resample", "encode", "mux"}
+
+// Run builds and executes the pipeline that decodes audio read from r and
+// encodes it into cfg's output format, writing the result to w. It is the
+// single pipeline builder shared by the HTTP convert form and the
+// convertgrpc service, so both entry points stay in lock-step as new
+// output formats are added. trim optionally restricts the conversion to
+// a sub-range of the input. progress may be nil; when set, it's invoked
+// as the pipeline moves through its decode/resample/encode/mux steps.
+func Run(r io.Reader, w io.Writer, cfg OutputConfig, trim TrimConfig, progress ProgressCallback) error {
+	report := func(step int, p float64) {
+		if progress != nil {
+			progress(step, pipelineSteps[step], p)
+		}
+	}
+
+	switch cfg := cfg.(type) {
+	case WavConfig:
+		return runWav(r, w, cfg, trim, report)
+	default:
+		return fmt.Errorf("convert: %s pipeline is not wired up yet", cfg.Format())
+	}
+}
+
+// runWav decodes PCM wav audio read from r and re-encodes it at cfg's bit
+// depth, writing the result to w. It's the only format Run actually
+// transcodes today; everything else is rejected earlier by CheckSupported.
+// trim, if non-zero, restricts the output to the [Start, End) frame range
+// it describes.
+func runWav(r io.Reader, w io.Writer, cfg WavConfig, trim TrimConfig, report func(step int, p float64)) error {
+	report(0, 0)
+	h, err := readWavHeader(r)
+	if err != nil {
+		return err
+	}
+	report(0, 1)
+
+	inBitDepth := h.BitDepth
+	outBitDepth := int(cfg.BitDepth)
+	if outBitDepth == 0 {
+		outBitDepth = inBitDepth
+	}
+
+	inBytes := inBitDepth / 8
+	if inBytes == 0 {
+		return fmt.Errorf("convert: unsupported wav bit depth: %d", inBitDepth)
+	}
+	totalFrames := h.DataSize / (h.Channels * inBytes)
+
+	startFrame, endFrame := trimFrameRange(trim, h.SampleRate, totalFrames)
+	frames := endFrame - startFrame
+
+	report(1, 1)
+	if err := writeWavHeader(w, h.SampleRate, h.Channels, outBitDepth, frames); err != nil {
+		return err
+	}
+
+	for i := 0; i < startFrame*h.Channels; i++ {
+		if _, err := readPCMSample(r, inBitDepth); err != nil {
+			return err
+		}
+	}
+
+	total := frames * h.Channels
+	for i := 0; i < total; i++ {
+		v, err := readPCMSample(r, inBitDepth)
+		if err != nil {
+			return err
+		}
+		if err := writePCMSample(w, v, outBitDepth); err != nil {
+			return err
+		}
+		if total > 0 && i%1024 == 0 {
+			report(2, float64(i)/float64(total))
+		}
+	}
+	report(2, 1)
+	report(3, 1)
+	return nil
+}
+
+// trimFrameRange converts trim into a [start, end) frame range over a
+// track of totalFrames frames at sampleRate, clamped to the track's
+// bounds. The zero TrimConfig covers the whole track.
+func trimFrameRange(trim TrimConfig, sampleRate, totalFrames int) (start, end int) {
+	start, end = 0, totalFrames
+	if trim.Start > 0 {
+		start = int(trim.Start.Seconds() * float64(sampleRate))
+	}
+	if trim.End > 0 {
+		end = int(trim.End.Seconds() * float64(sampleRate))
+	}
+	if start < 0 {
+		start = 0
+	}
+	if start > totalFrames {
+		start = totalFrames
+	}
+	if end > totalFrames {
+		end = totalFrames
+	}
+	if end < start {
+		end = start
+	}
+	return start, end
+}
@@ -0,0 +1,79 @@
+package convert
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func writeTestPCMWav(t *testing.T, sampleRate, channels, bitDepth int, samples []int32) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := writeWavHeader(&buf, sampleRate, channels, bitDepth, len(samples)/channels); err != nil {
+		t.Fatalf("writeWavHeader: %v", err)
+	}
+	for _, v := range samples {
+		if err := writePCMSample(&buf, v, bitDepth); err != nil {
+			t.Fatalf("writePCMSample: %v", err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestRunWavBitDepthRoundTrip(t *testing.T) {
+	samples := []int32{1 << 28, -(1 << 28)}
+	src := writeTestPCMWav(t, 8000, 1, 16, samples)
+
+	var dst bytes.Buffer
+	if err := Run(bytes.NewReader(src), &dst, WavConfig{BitDepth: 8}, TrimConfig{}, nil); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	h, err := readWavHeader(bytes.NewReader(dst.Bytes()))
+	if err != nil {
+		t.Fatalf("readWavHeader: %v", err)
+	}
+	if h.BitDepth != 8 || h.Channels != 1 || h.SampleRate != 8000 {
+		t.Fatalf("got header %+v, want 8 bit, 1 channel, 8000 Hz", h)
+	}
+
+	data := dst.Bytes()[44:]
+	if len(data) != len(samples) {
+		t.Fatalf("got %d output bytes, want %d", len(data), len(samples))
+	}
+	for i, v := range samples {
+		want := byte((v >> 24) + 128)
+		if data[i] != want {
+			t.Errorf("sample %d = %d, want %d", i, data[i], want)
+		}
+	}
+}
+
+func TestRunWavTrim(t *testing.T) {
+	// 4 frames at 4 frames/sec; trimming to [250ms, 500ms) keeps only frame 1.
+	samples := []int32{10 << 16, 20 << 16, 30 << 16, 40 << 16}
+	src := writeTestPCMWav(t, 4, 1, 16, samples)
+
+	trim := TrimConfig{Start: 250 * time.Millisecond, End: 500 * time.Millisecond}
+	var dst bytes.Buffer
+	if err := Run(bytes.NewReader(src), &dst, WavConfig{}, trim, nil); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	h, err := readWavHeader(bytes.NewReader(dst.Bytes()))
+	if err != nil {
+		t.Fatalf("readWavHeader: %v", err)
+	}
+	frames := h.DataSize / (h.Channels * (h.BitDepth / 8))
+	if frames != 1 {
+		t.Fatalf("got %d output frames, want 1", frames)
+	}
+
+	v, err := readPCMSample(bytes.NewReader(dst.Bytes()[44:]), h.BitDepth)
+	if err != nil {
+		t.Fatalf("readPCMSample: %v", err)
+	}
+	if v != samples[1] {
+		t.Errorf("got sample %d, want %d", v, samples[1])
+	}
+}
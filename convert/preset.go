@@ -0,0 +1,156 @@
+package convert
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/pipelined/mp3"
+	"github.com/pipelined/signal"
+	"gopkg.in/yaml.v2"
+)
+
+// Preset is a named, pre-configured output profile. Presets let users
+// apply common conversions in one click, and let sysadmins lock down the
+// set of allowed output configurations centrally.
+type Preset struct {
+	Name        string
+	Description string
+	Config      OutputConfig
+}
+
+// Presets is the set of named conversion profiles loaded at startup by
+// LoadPresets. It's empty until LoadPresets is called.
+var Presets []Preset
+
+// PresetByName looks up a loaded preset by name.
+func PresetByName(name string) (Preset, bool) {
+	for _, p := range Presets {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Preset{}, false
+}
+
+// presetSpec is the on-disk shape of a single preset entry.
+type presetSpec struct {
+	Format           string `yaml:"format" json:"format"`
+	BitDepth         int    `yaml:"bit_depth,omitempty" json:"bit_depth,omitempty"`
+	BitRateMode      string `yaml:"bit_rate_mode,omitempty" json:"bit_rate_mode,omitempty"`
+	BitRate          int    `yaml:"bit_rate,omitempty" json:"bit_rate,omitempty"`
+	VBRQuality       int    `yaml:"vbr_quality,omitempty" json:"vbr_quality,omitempty"`
+	ChannelMode      string `yaml:"channel_mode,omitempty" json:"channel_mode,omitempty"`
+	CompressionLevel int    `yaml:"compression_level,omitempty" json:"compression_level,omitempty"`
+	BlockSize        int    `yaml:"block_size,omitempty" json:"block_size,omitempty"`
+	SampleFormat     int    `yaml:"sample_format,omitempty" json:"sample_format,omitempty"`
+}
+
+// LoadPresets reads named conversion profiles from the YAML or JSON file
+// at path, keyed by preset name, and stores them in Presets.
+func LoadPresets(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed reading presets file %s: %v", path, err)
+	}
+
+	specs := map[string]presetSpec{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &specs)
+	default:
+		err = yaml.Unmarshal(data, &specs)
+	}
+	if err != nil {
+		return fmt.Errorf("failed parsing presets file %s: %v", path, err)
+	}
+
+	presets := make([]Preset, 0, len(specs))
+	for name, spec := range specs {
+		cfg, err := spec.outputConfig()
+		if err != nil {
+			return fmt.Errorf("failed parsing preset %s: %v", name, err)
+		}
+		if err := CheckSupported(cfg); err != nil {
+			return fmt.Errorf("failed parsing preset %s: %v", name, err)
+		}
+		presets = append(presets, Preset{
+			Name:        name,
+			Description: spec.describe(),
+			Config:      cfg,
+		})
+	}
+	Presets = presets
+	return nil
+}
+
+// outputConfig builds the OutputConfig described by the preset spec.
+func (s presetSpec) outputConfig() (OutputConfig, error) {
+	switch Format(strings.ToLower(s.Format)) {
+	case WavFormat:
+		return WavConfig{BitDepth: signal.BitDepth(s.BitDepth)}, nil
+	case Mp3Format:
+		bitRateMode, err := mp3BitRateModeFromString(s.BitRateMode)
+		if err != nil {
+			return nil, err
+		}
+		return Mp3Config{
+			BitRateMode: bitRateMode,
+			ChannelMode: mp3ChannelModeFromString(s.ChannelMode),
+			BitRate:     s.BitRate,
+			VBRQuality:  mp3.VBRQuality(s.VBRQuality),
+		}, nil
+	case FlacFormat:
+		return FlacConfig{CompressionLevel: s.CompressionLevel, BlockSize: s.BlockSize}, nil
+	case AlacFormat:
+		return AlacConfig{SampleFormat: signal.BitDepth(s.SampleFormat)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported format: %v", s.Format)
+	}
+}
+
+// describe renders a short, human readable summary of the preset, shown
+// next to its name in the convert form's preset dropdown.
+func (s presetSpec) describe() string {
+	switch Format(strings.ToLower(s.Format)) {
+	case WavFormat:
+		return fmt.Sprintf("wav, %d bit", s.BitDepth)
+	case Mp3Format:
+		if strings.EqualFold(s.BitRateMode, "vbr") {
+			return fmt.Sprintf("mp3, VBR %d, %s", s.VBRQuality, s.ChannelMode)
+		}
+		return fmt.Sprintf("mp3, %s %dkbps, %s", strings.ToUpper(s.BitRateMode), s.BitRate, s.ChannelMode)
+	case FlacFormat:
+		return fmt.Sprintf("flac, level %d", s.CompressionLevel)
+	case AlacFormat:
+		return fmt.Sprintf("alac, %d bit", s.SampleFormat)
+	default:
+		return s.Format
+	}
+}
+
+func mp3BitRateModeFromString(s string) (mp3.BitRateMode, error) {
+	switch strings.ToUpper(s) {
+	case "", "CBR":
+		return mp3.CBR, nil
+	case "ABR":
+		return mp3.ABR, nil
+	case "VBR":
+		return mp3.VBR, nil
+	default:
+		return 0, fmt.Errorf("unsupported bit rate mode: %s", s)
+	}
+}
+
+func mp3ChannelModeFromString(s string) mp3.ChannelMode {
+	switch strings.ToLower(s) {
+	case "mono":
+		return mp3.Mono
+	case "joint_stereo":
+		return mp3.JointStereo
+	default:
+		return mp3.Stereo
+	}
+}
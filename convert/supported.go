@@ -0,0 +1,56 @@
+package convert
+
+import (
+	"github.com/pipelined/mp3"
+	"github.com/pipelined/signal"
+)
+
+// supported is a list of options that are available per output format.
+type supported struct {
+	WavBitDepths          map[signal.BitDepth]string
+	Mp3BitRateModes       map[mp3.BitRateMode]string
+	Mp3ChannelModes       map[mp3.ChannelMode]string
+	FlacCompressionLevels map[int]string
+	FlacBlockSizes        map[int]string
+	AlacSampleFormats     map[signal.BitDepth]string
+}
+
+// Supported lists all options allowed for conversion, per output format.
+var Supported = supported{
+	WavBitDepths: map[signal.BitDepth]string{
+		signal.BitDepth8:  "8 bit",
+		signal.BitDepth16: "16 bit",
+		signal.BitDepth24: "24 bit",
+		signal.BitDepth32: "32 bit",
+	},
+	Mp3BitRateModes: map[mp3.BitRateMode]string{
+		mp3.VBR: "VBR",
+		mp3.ABR: "ABR",
+		mp3.CBR: "CBR",
+	},
+	Mp3ChannelModes: map[mp3.ChannelMode]string{
+		mp3.JointStereo: "Joint Stereo",
+		mp3.Stereo:      "Stereo",
+		mp3.Mono:        "Mono",
+	},
+	FlacCompressionLevels: map[int]string{
+		0: "0 (fastest)",
+		1: "1",
+		2: "2",
+		3: "3",
+		4: "4",
+		5: "5 (default)",
+		6: "6",
+		7: "7",
+		8: "8 (best)",
+	},
+	FlacBlockSizes: map[int]string{
+		0:    "default",
+		4096: "4096",
+		8192: "8192",
+	},
+	AlacSampleFormats: map[signal.BitDepth]string{
+		signal.BitDepth16: "16 bit",
+		signal.BitDepth24: "24 bit",
+	},
+}
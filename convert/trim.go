@@ -0,0 +1,13 @@
+package convert
+
+import "time"
+
+// TrimConfig optionally restricts a conversion to the [Start, End) range
+// of the input audio, as selected via the waveform preview's drag
+// handles. The zero TrimConfig means "no trim": convert the whole file.
+// Run honors it for every format whose pipeline is wired up; see
+// CheckSupported for which formats that currently includes.
+type TrimConfig struct {
+	Start time.Duration
+	End   time.Duration
+}
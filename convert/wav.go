@@ -0,0 +1,104 @@
+package convert
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/pipelined/signal"
+)
+
+// WavConfig is a wav output configuration.
+type WavConfig struct {
+	BitDepth signal.BitDepth
+}
+
+// Format returns WavFormat.
+func (WavConfig) Format() Format {
+	return WavFormat
+}
+
+// writeWavHeader writes the RIFF/fmt/data chunks for a PCM wav file holding
+// frames frames of channels-channel audio at sampleRate, bitDepth bits per
+// sample.
+func writeWavHeader(w io.Writer, sampleRate, channels, bitDepth, frames int) error {
+	blockAlign := channels * bitDepth / 8
+	dataSize := frames * blockAlign
+
+	var header [44]byte
+	copy(header[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:8], uint32(36+dataSize))
+	copy(header[8:12], "WAVE")
+	copy(header[12:16], "fmt ")
+	binary.LittleEndian.PutUint32(header[16:20], 16)
+	binary.LittleEndian.PutUint16(header[20:22], 1) // PCM
+	binary.LittleEndian.PutUint16(header[22:24], uint16(channels))
+	binary.LittleEndian.PutUint32(header[24:28], uint32(sampleRate))
+	binary.LittleEndian.PutUint32(header[28:32], uint32(sampleRate*blockAlign))
+	binary.LittleEndian.PutUint16(header[32:34], uint16(blockAlign))
+	binary.LittleEndian.PutUint16(header[34:36], uint16(bitDepth))
+	copy(header[36:40], "data")
+	binary.LittleEndian.PutUint32(header[40:44], uint32(dataSize))
+
+	_, err := w.Write(header[:])
+	return err
+}
+
+// readPCMSample reads one bitDepth-wide PCM sample off r and returns it
+// normalized to a signed 32 bit range, so samples of different bit depths
+// can be compared and re-quantized on a common scale.
+func readPCMSample(r io.Reader, bitDepth int) (int32, error) {
+	buf := make([]byte, bitDepth/8)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return 0, err
+	}
+
+	switch bitDepth {
+	case 8:
+		// 8 bit PCM is unsigned, centered on 128.
+		return (int32(buf[0]) - 128) << 24, nil
+	case 16:
+		return int32(int16(binary.LittleEndian.Uint16(buf))) << 16, nil
+	case 24:
+		v := int32(buf[0]) | int32(buf[1])<<8 | int32(buf[2])<<16
+		v <<= 8
+		v >>= 8 // sign-extend the 24 bit value
+		return v << 8, nil
+	case 32:
+		return int32(binary.LittleEndian.Uint32(buf)), nil
+	default:
+		return 0, errInvalidBitDepth(bitDepth)
+	}
+}
+
+// writePCMSample writes v (normalized to a signed 32 bit range, as returned
+// by readPCMSample) to w, quantized down to bitDepth bits per sample.
+func writePCMSample(w io.Writer, v int32, bitDepth int) error {
+	switch bitDepth {
+	case 8:
+		b := byte((v >> 24) + 128)
+		_, err := w.Write([]byte{b})
+		return err
+	case 16:
+		var buf [2]byte
+		binary.LittleEndian.PutUint16(buf[:], uint16(v>>16))
+		_, err := w.Write(buf[:])
+		return err
+	case 24:
+		s := v >> 8
+		buf := []byte{byte(s), byte(s >> 8), byte(s >> 16)}
+		_, err := w.Write(buf)
+		return err
+	case 32:
+		var buf [4]byte
+		binary.LittleEndian.PutUint32(buf[:], uint32(v))
+		_, err := w.Write(buf[:])
+		return err
+	default:
+		return errInvalidBitDepth(bitDepth)
+	}
+}
+
+func errInvalidBitDepth(bitDepth int) error {
+	return fmt.Errorf("convert: unsupported wav bit depth: %d", bitDepth)
+}
@@ -0,0 +1,58 @@
+// This file hand-mirrors the ConvertService client API declared in
+// convert.proto; see types.go and codec.go for why it isn't protoc
+// output and how it's kept working without one. Keep it in sync with
+// convert.proto and service.go by hand until real generated code lands.
+
+package convertgrpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// ConvertServiceClient is the client API for ConvertService. Dial a
+// *grpc.ClientConn with convertgrpc.Dial so its codec matches the server.
+type ConvertServiceClient interface {
+	ConvertAudio(ctx context.Context, opts ...grpc.CallOption) (ConvertService_ConvertAudioClient, error)
+}
+
+// NewConvertServiceClient returns a ConvertServiceClient backed by cc.
+func NewConvertServiceClient(cc grpc.ClientConnInterface) ConvertServiceClient {
+	return &convertServiceClient{cc}
+}
+
+type convertServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func (c *convertServiceClient) ConvertAudio(ctx context.Context, opts ...grpc.CallOption) (ConvertService_ConvertAudioClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ConvertService_serviceDesc.Streams[0], "/convertgrpc.ConvertService/ConvertAudio", opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &convertServiceConvertAudioClient{stream}, nil
+}
+
+// ConvertService_ConvertAudioClient is the client-side stream for ConvertAudio.
+type ConvertService_ConvertAudioClient interface {
+	Send(*ConvertRequest) error
+	Recv() (*ConvertResponse, error)
+	grpc.ClientStream
+}
+
+type convertServiceConvertAudioClient struct {
+	grpc.ClientStream
+}
+
+func (x *convertServiceConvertAudioClient) Send(m *ConvertRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *convertServiceConvertAudioClient) Recv() (*ConvertResponse, error) {
+	m := new(ConvertResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
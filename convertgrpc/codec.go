@@ -0,0 +1,72 @@
+// This file backs ConvertService with an actual working wire codec. The
+// types in types.go aren't real proto.Message values (no Reset/String/
+// ProtoReflect), so grpc-go's default "proto" codec can't marshal them —
+// the first SendMsg/RecvMsg against this service would fail. Rather than
+// ship a service that's registered but guaranteed to error on first use,
+// gobCodec stands in for the real protobuf codec under its own
+// content-subtype name, so ConvertAudio actually works end to end between
+// NewServer and Dial. It is NOT wire-compatible with a real protoc-gen-go
+// client (grpcurl, a stub generated from convert.proto, ...); replacing it
+// with real generated types is the eventual fix, tracked alongside
+// types.go and service.go.
+package convertgrpc
+
+import (
+	"bytes"
+	"encoding/gob"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+)
+
+func init() {
+	gob.Register(&ConvertRequest_Config{})
+	gob.Register(&ConvertRequest_Chunk{})
+	gob.Register(&ConvertResponse_Chunk{})
+	gob.Register(&ConvertResponse_Status{})
+	encoding.RegisterCodec(gobCodec{})
+}
+
+// codecName is registered as its own content-subtype, rather than
+// overriding grpc's built-in "proto" codec, so any other gRPC service
+// sharing this process stays on the real protobuf codec.
+const codecName = "phono-gob"
+
+// gobCodec implements grpc/encoding.Codec with encoding/gob.
+type gobCodec struct{}
+
+// Marshal implements encoding.Codec.
+func (gobCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal implements encoding.Codec.
+func (gobCodec) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// Name implements encoding.Codec.
+func (gobCodec) Name() string {
+	return codecName
+}
+
+// NewServer returns a *grpc.Server that exchanges ConvertService messages
+// using gobCodec. Always use this instead of grpc.NewServer to serve
+// ConvertService: plain grpc.NewServer would fall back to the default
+// protobuf codec, which can't marshal the hand-written types in types.go.
+func NewServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append([]grpc.ServerOption{grpc.ForceServerCodec(gobCodec{})}, opts...)
+	return grpc.NewServer(opts...)
+}
+
+// Dial connects to a ConvertService server started with NewServer,
+// configuring the client to use the matching codec. Always use this
+// instead of grpc.Dial to call ConvertService.
+func Dial(target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	opts = append([]grpc.DialOption{grpc.WithDefaultCallOptions(grpc.ForceCodec(gobCodec{}))}, opts...)
+	return grpc.Dial(target, opts...)
+}
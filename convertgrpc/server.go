@@ -0,0 +1,163 @@
+// Package convertgrpc exposes phono's audio conversion over gRPC, so
+// non-browser clients (CLI tools, other services) can convert audio
+// without scraping the HTML convert form served by the template package.
+// Serve it with NewServer and call it with Dial/NewConvertServiceClient,
+// not the plain grpc.NewServer/grpc.Dial — see codec.go for why.
+package convertgrpc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/pipelined/mp3"
+	"github.com/pipelined/phono/convert"
+	"github.com/pipelined/signal"
+)
+
+// chunkWriterBufSize batches output bytes into ConvertResponse chunk
+// messages of roughly this size, instead of sending a message per PCM
+// sample written by convert.Run.
+const chunkWriterBufSize = 32 * 1024
+
+// Server implements ConvertServiceServer on top of convert.OutputConfig,
+// so it shares the exact same pipeline builder as the HTTP convert form.
+type Server struct{}
+
+// ConvertAudio receives a Config message followed by a stream of input
+// file chunks, and streams back the converted output followed by a
+// terminal Status.
+func (Server) ConvertAudio(stream ConvertService_ConvertAudioServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	config := req.GetConfig()
+	if config == nil {
+		return fmt.Errorf("first message must carry a config")
+	}
+
+	cfg, err := outputConfig(config)
+	if err != nil {
+		return sendStatus(stream, err)
+	}
+	if err := convert.CheckSupported(cfg); err != nil {
+		return sendStatus(stream, err)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err == io.EOF {
+				pw.Close()
+				return
+			}
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			if _, err := pw.Write(req.GetChunk()); err != nil {
+				return
+			}
+		}
+	}()
+
+	w := bufio.NewWriterSize(&chunkWriter{stream: stream}, chunkWriterBufSize)
+	if err := convert.Run(pr, w, cfg, convert.TrimConfig{}, nil); err != nil {
+		return sendStatus(stream, err)
+	}
+	if err := w.Flush(); err != nil {
+		return sendStatus(stream, err)
+	}
+	return sendStatus(stream, nil)
+}
+
+// outputConfig converts a wire Config into the convert.OutputConfig it
+// describes.
+func outputConfig(config *Config) (convert.OutputConfig, error) {
+	switch config.Format {
+	case AudioFormat_WAV:
+		o := config.WavOptions
+		if o == nil {
+			return nil, fmt.Errorf("wav format requires wav_options")
+		}
+		return convert.WavConfig{BitDepth: signal.BitDepth(o.BitDepth)}, nil
+	case AudioFormat_MP3:
+		o := config.Mp3Options
+		if o == nil {
+			return nil, fmt.Errorf("mp3 format requires mp3_options")
+		}
+		return convert.Mp3Config{
+			BitRateMode: mp3BitRateMode(o.BitRateMode),
+			ChannelMode: mp3ChannelMode(o.ChannelMode),
+			BitRate:     int(o.BitRate),
+			VBRQuality:  mp3.VBRQuality(o.VbrQuality),
+		}, nil
+	case AudioFormat_FLAC:
+		o := config.FlacOptions
+		if o == nil {
+			return nil, fmt.Errorf("flac format requires flac_options")
+		}
+		return convert.FlacConfig{
+			CompressionLevel: int(o.CompressionLevel),
+			BlockSize:        int(o.BlockSize),
+		}, nil
+	case AudioFormat_ALAC:
+		o := config.AlacOptions
+		if o == nil {
+			return nil, fmt.Errorf("alac format requires alac_options")
+		}
+		return convert.AlacConfig{SampleFormat: signal.BitDepth(o.SampleFormat)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported format: %v", config.Format)
+	}
+}
+
+// mp3BitRateMode maps the wire bit rate mode to its convert/mp3 equivalent.
+func mp3BitRateMode(m Mp3Options_BitRateMode) mp3.BitRateMode {
+	switch m {
+	case Mp3Options_ABR:
+		return mp3.ABR
+	case Mp3Options_VBR:
+		return mp3.VBR
+	default:
+		return mp3.CBR
+	}
+}
+
+// mp3ChannelMode maps the wire channel mode to its convert/mp3 equivalent.
+func mp3ChannelMode(m Mp3Options_ChannelMode) mp3.ChannelMode {
+	switch m {
+	case Mp3Options_JOINT_STEREO:
+		return mp3.JointStereo
+	case Mp3Options_MONO:
+		return mp3.Mono
+	default:
+		return mp3.Stereo
+	}
+}
+
+// chunkWriter streams encoded output bytes back to the client as a
+// sequence of ConvertResponse chunk messages.
+type chunkWriter struct {
+	stream ConvertService_ConvertAudioServer
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	chunk := make([]byte, len(p))
+	copy(chunk, p)
+	if err := w.stream.Send(&ConvertResponse{Payload: &ConvertResponse_Chunk{Chunk: chunk}}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// sendStatus sends the terminal status message for the stream.
+func sendStatus(stream ConvertService_ConvertAudioServer, err error) error {
+	status := &Status{Ok: err == nil}
+	if err != nil {
+		status.Error = err.Error()
+	}
+	return stream.Send(&ConvertResponse{Payload: &ConvertResponse_Status{Status: status}})
+}
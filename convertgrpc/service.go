@@ -0,0 +1,63 @@
+// This file hand-mirrors the ConvertService server API declared in
+// convert.proto. It is NOT output from protoc-gen-go-grpc: see types.go
+// and codec.go for why the messages it carries aren't real proto.Message
+// values and how RegisterConvertServiceServer still works despite that.
+// Keep this file in sync with convert.proto and client.go by hand until
+// real generated code replaces them.
+
+package convertgrpc
+
+import (
+	"google.golang.org/grpc"
+)
+
+// ConvertServiceServer is the server API for ConvertService.
+type ConvertServiceServer interface {
+	ConvertAudio(ConvertService_ConvertAudioServer) error
+}
+
+// ConvertService_ConvertAudioServer is the server-side stream for ConvertAudio.
+type ConvertService_ConvertAudioServer interface {
+	Send(*ConvertResponse) error
+	Recv() (*ConvertRequest, error)
+	grpc.ServerStream
+}
+
+// RegisterConvertServiceServer registers srv as the ConvertService implementation.
+func RegisterConvertServiceServer(s *grpc.Server, srv ConvertServiceServer) {
+	s.RegisterService(&_ConvertService_serviceDesc, srv)
+}
+
+var _ConvertService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "convertgrpc.ConvertService",
+	HandlerType: (*ConvertServiceServer)(nil),
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ConvertAudio",
+			Handler:       _ConvertService_ConvertAudio_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "convert.proto",
+}
+
+func _ConvertService_ConvertAudio_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ConvertServiceServer).ConvertAudio(&convertServiceConvertAudioServer{stream})
+}
+
+type convertServiceConvertAudioServer struct {
+	grpc.ServerStream
+}
+
+func (x *convertServiceConvertAudioServer) Send(m *ConvertResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *convertServiceConvertAudioServer) Recv() (*ConvertRequest, error) {
+	m := new(ConvertRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
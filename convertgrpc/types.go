@@ -0,0 +1,155 @@
+// This file hand-mirrors the message shapes declared in convert.proto. It
+// is NOT output from protoc-gen-go, and these types do not implement
+// proto.Message (no Reset/String/ProtoReflect, no field descriptors), so
+// they are not wire-compatible with grpc-go's default protobuf codec or
+// with a standards-compliant gRPC client (grpcurl, a stub generated from
+// convert.proto, ...). codec.go registers gobCodec as a stand-in wire
+// format so ConvertService still works end to end for clients that go
+// through convertgrpc.NewServer/Dial. Treat convert.proto as the source
+// of truth and keep these structs in sync with it by hand until real
+// generated code replaces this file and codec.go is no longer needed.
+
+package convertgrpc
+
+// AudioFormat lists the audio formats supported by the convert service.
+type AudioFormat int32
+
+// Supported audio formats.
+const (
+	AudioFormat_WAV  AudioFormat = 0
+	AudioFormat_MP3  AudioFormat = 1
+	AudioFormat_FLAC AudioFormat = 2
+	AudioFormat_ALAC AudioFormat = 3
+)
+
+// WavOptions carries wav-specific encoding options.
+type WavOptions struct {
+	BitDepth int32
+}
+
+// Mp3Options carries mp3-specific encoding options.
+type Mp3Options struct {
+	BitRateMode Mp3Options_BitRateMode
+	ChannelMode Mp3Options_ChannelMode
+	BitRate     int32
+	VbrQuality  int32
+}
+
+// Mp3Options_BitRateMode lists the mp3 bit rate modes.
+type Mp3Options_BitRateMode int32
+
+// Supported mp3 bit rate modes.
+const (
+	Mp3Options_CBR Mp3Options_BitRateMode = 0
+	Mp3Options_ABR Mp3Options_BitRateMode = 1
+	Mp3Options_VBR Mp3Options_BitRateMode = 2
+)
+
+// Mp3Options_ChannelMode lists the mp3 channel modes.
+type Mp3Options_ChannelMode int32
+
+// Supported mp3 channel modes.
+const (
+	Mp3Options_STEREO       Mp3Options_ChannelMode = 0
+	Mp3Options_JOINT_STEREO Mp3Options_ChannelMode = 1
+	Mp3Options_MONO         Mp3Options_ChannelMode = 2
+)
+
+// FlacOptions carries flac-specific encoding options.
+type FlacOptions struct {
+	CompressionLevel int32
+	BlockSize        int32
+}
+
+// AlacOptions carries alac-specific encoding options.
+type AlacOptions struct {
+	SampleFormat int32
+}
+
+// Config is the first message of a ConvertAudio stream. It selects the
+// output format and carries its format-specific options.
+type Config struct {
+	Format      AudioFormat
+	WavOptions  *WavOptions
+	Mp3Options  *Mp3Options
+	FlacOptions *FlacOptions
+	AlacOptions *AlacOptions
+}
+
+// ConvertRequest is streamed to the server: the first message carries the
+// Config, every following message carries a chunk of the input file.
+type ConvertRequest struct {
+	// Payload is one of *ConvertRequest_Config or *ConvertRequest_Chunk.
+	Payload isConvertRequest_Payload
+}
+
+type isConvertRequest_Payload interface {
+	isConvertRequest_Payload()
+}
+
+// ConvertRequest_Config carries the config payload.
+type ConvertRequest_Config struct {
+	Config *Config
+}
+
+// ConvertRequest_Chunk carries an input file chunk payload.
+type ConvertRequest_Chunk struct {
+	Chunk []byte
+}
+
+func (*ConvertRequest_Config) isConvertRequest_Payload() {}
+func (*ConvertRequest_Chunk) isConvertRequest_Payload()  {}
+
+// GetConfig returns the config payload, or nil if the request carries a chunk.
+func (r *ConvertRequest) GetConfig() *Config {
+	if c, ok := r.GetPayload().(*ConvertRequest_Config); ok {
+		return c.Config
+	}
+	return nil
+}
+
+// GetChunk returns the chunk payload, or nil if the request carries a config.
+func (r *ConvertRequest) GetChunk() []byte {
+	if c, ok := r.GetPayload().(*ConvertRequest_Chunk); ok {
+		return c.Chunk
+	}
+	return nil
+}
+
+// GetPayload returns the request's payload.
+func (r *ConvertRequest) GetPayload() isConvertRequest_Payload {
+	if r == nil {
+		return nil
+	}
+	return r.Payload
+}
+
+// Status is the terminal message of a ConvertAudio stream.
+type Status struct {
+	Ok    bool
+	Error string
+}
+
+// ConvertResponse is streamed back to the client: converted output bytes
+// followed by a terminal Status once the input stream is fully consumed.
+type ConvertResponse struct {
+	// Payload is one of *ConvertResponse_Chunk or *ConvertResponse_Status.
+	Payload isConvertResponse_Payload
+}
+
+type isConvertResponse_Payload interface {
+	isConvertResponse_Payload()
+}
+
+// ConvertResponse_Chunk carries an output file chunk payload.
+type ConvertResponse_Chunk struct {
+	Chunk []byte
+}
+
+// ConvertResponse_Status carries the terminal status payload.
+type ConvertResponse_Status struct {
+	Status *Status
+}
+
+func (*ConvertResponse_Chunk) isConvertResponse_Payload()  {}
+func (*ConvertResponse_Status) isConvertResponse_Payload() {}
@@ -2,12 +2,15 @@ package template
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"mime/multipart"
 	"net/http"
 	"path"
 	"strconv"
+	"strings"
 	"text/template"
+	"time"
 
 	"github.com/pipelined/mp3"
 	"github.com/pipelined/phono/convert"
@@ -20,10 +23,13 @@ type convertForm struct {
 
 // convertData provides a data for convert form, so user can define conversion parameters.
 type convertData struct {
-	Accept     string
-	OutFormats []convert.Format
-	WavOptions wavOptions
-	Mp3Options mp3Options
+	Accept      string
+	OutFormats  []convert.Format
+	WavOptions  wavOptions
+	Mp3Options  mp3Options
+	FlacOptions flacOptions
+	AlacOptions alacOptions
+	Presets     []convert.Preset
 }
 
 // WavOptions is a struct of wav options that are available for conversion.
@@ -40,15 +46,23 @@ type mp3Options struct {
 	DefineQuality bool
 }
 
+// flacOptions is a struct of flac options that are available for conversion.
+type flacOptions struct {
+	CompressionLevels map[int]string
+	BlockSizes        map[int]string
+}
+
+// alacOptions is a struct of alac options that are available for conversion.
+type alacOptions struct {
+	SampleFormats map[signal.BitDepth]string
+}
+
 var (
 	convertTemplate = template.Must(template.New("convert").Parse(convertHTML))
 
 	convertFormData = convertData{
-		Accept: fmt.Sprintf(".%s, .%s", convert.WavFormat, convert.Mp3Format),
-		OutFormats: []convert.Format{
-			convert.WavFormat,
-			convert.Mp3Format,
-		},
+		Accept:     acceptExtensions(),
+		OutFormats: outFormats(),
 		WavOptions: wavOptions{
 			BitDepths: convert.Supported.WavBitDepths,
 		},
@@ -59,12 +73,60 @@ var (
 			BitRateModes: convert.Supported.Mp3BitRateModes,
 			ChannelModes: convert.Supported.Mp3ChannelModes,
 		},
+		FlacOptions: flacOptions{
+			CompressionLevels: convert.Supported.FlacCompressionLevels,
+			BlockSizes:        convert.Supported.FlacBlockSizes,
+		},
+		AlacOptions: alacOptions{
+			SampleFormats: convert.Supported.AlacSampleFormats,
+		},
+		Presets: convert.Presets,
 	}
 
 	// ConvertForm is the convert form.
 	ConvertForm = parseConvertForm()
 )
 
+// ReloadPresets rebuilds the convert form from whatever presets are
+// currently loaded in convert.Presets. Call it once at startup, after
+// convert.LoadPresets, so the preset dropdown reflects the configured
+// profiles.
+func ReloadPresets() {
+	convertFormData.Presets = convert.Presets
+	ConvertForm = parseConvertForm()
+}
+
+// outFormats lists every output format offered by the convert form: the
+// built-in wav/mp3 plus every lossless codec registered in
+// convert.LosslessFormats, minus whichever of those convert.IsSupported
+// says aren't wired up in Run yet. A format that's always going to
+// dead-end at convert.CheckSupported shouldn't be selectable in the
+// first place.
+func outFormats() []convert.Format {
+	all := []convert.Format{convert.WavFormat, convert.Mp3Format}
+	for _, lf := range convert.LosslessFormats {
+		all = append(all, lf.Format)
+	}
+	var formats []convert.Format
+	for _, f := range all {
+		if convert.IsSupported(f) {
+			formats = append(formats, f)
+		}
+	}
+	return formats
+}
+
+// acceptExtensions builds the <input accept> attribute value from the
+// formats offered by the convert form.
+func acceptExtensions() string {
+	formats := outFormats()
+	exts := make([]string, len(formats))
+	for i, f := range formats {
+		exts[i] = "." + string(f)
+	}
+	return strings.Join(exts, ", ")
+}
+
 func parseConvertForm() convertForm {
 	var b bytes.Buffer
 	if err := convertTemplate.Execute(&b, convertFormData); err != nil {
@@ -88,17 +150,102 @@ func (convertForm) File(r *http.Request) (multipart.File, *multipart.FileHeader,
 	return r.FormFile("input-file")
 }
 
-// Prase form data into output config.
-func (convertForm) Parse(r *http.Request) (convert.OutputConfig, error) {
+// Prase form data into output config and optional trim range.
+func (convertForm) Parse(r *http.Request) (convert.OutputConfig, convert.TrimConfig, error) {
+	trim, err := parseTrimConfig(r)
+	if err != nil {
+		return nil, convert.TrimConfig{}, err
+	}
+
+	if name := r.FormValue("preset"); name != "" {
+		preset, ok := convert.PresetByName(name)
+		if !ok {
+			return nil, convert.TrimConfig{}, fmt.Errorf("Unknown preset: %v", name)
+		}
+		return preset.Config, trim, nil
+	}
+
 	f := convert.Format(r.FormValue("format"))
+	var cfg convert.OutputConfig
 	switch f {
 	case convert.WavFormat:
-		return parseWavConfig(r)
+		cfg, err = parseWavConfig(r)
 	case convert.Mp3Format:
-		return parseMp3Config(r)
+		cfg, err = parseMp3Config(r)
+	case convert.FlacFormat:
+		cfg, err = parseFlacConfig(r)
+	case convert.AlacFormat:
+		cfg, err = parseAlacConfig(r)
 	default:
-		return nil, fmt.Errorf("Unsupported format: %v", f)
+		return nil, convert.TrimConfig{}, fmt.Errorf("Unsupported format: %v", f)
+	}
+	if err != nil {
+		return nil, convert.TrimConfig{}, err
 	}
+	// Reject formats whose pipeline isn't wired up yet here, at the form's
+	// validation boundary, instead of letting the upload stream all the
+	// way into Run before failing.
+	if err := convert.CheckSupported(cfg); err != nil {
+		return nil, convert.TrimConfig{}, err
+	}
+	return cfg, trim, nil
+}
+
+// parseTrimConfig parses the optional trim range from the html form. Both
+// fields are milliseconds from the start of the file; either or both may
+// be omitted, in which case the file is converted in full.
+func parseTrimConfig(r *http.Request) (convert.TrimConfig, error) {
+	var cfg convert.TrimConfig
+	if v := r.FormValue("trim-start"); v != "" {
+		ms, err := strconv.Atoi(v)
+		if err != nil {
+			return convert.TrimConfig{}, fmt.Errorf("Failed parsing trim start %s: %v", v, err)
+		}
+		cfg.Start = time.Duration(ms) * time.Millisecond
+	}
+	if v := r.FormValue("trim-end"); v != "" {
+		ms, err := strconv.Atoi(v)
+		if err != nil {
+			return convert.TrimConfig{}, fmt.Errorf("Failed parsing trim end %s: %v", v, err)
+		}
+		cfg.End = time.Duration(ms) * time.Millisecond
+	}
+	return cfg, nil
+}
+
+// Enqueue parses the output config from the request and starts converting
+// the uploaded file in the background, returning the job that tracks its
+// progress. Use convert.JobStatusHandler and convert.JobDownloadHandler to
+// poll it and fetch the result once it's done.
+func (cf convertForm) Enqueue(r *http.Request) (*convert.Job, error) {
+	cfg, trim, err := cf.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	file, _, err := cf.File(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.Jobs.Enqueue(file, cfg, trim), nil
+}
+
+// EnqueueHandler serves POST /jobs: it parses the request the same way
+// Enqueue does, starts the conversion in the background, and responds
+// with the new job's id, so the submitting client can poll
+// convert.JobStatusHandler for progress and fetch convert.JobDownloadHandler
+// once it's done.
+func (cf convertForm) EnqueueHandler(w http.ResponseWriter, r *http.Request) {
+	job, err := cf.Enqueue(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		ID string `json:"id"`
+	}{ID: job.ID})
 }
 
 func parseWavConfig(r *http.Request) (convert.WavConfig, error) {
@@ -149,6 +296,32 @@ func parseMp3Config(r *http.Request) (convert.Mp3Config, error) {
 	}, nil
 }
 
+func parseFlacConfig(r *http.Request) (convert.FlacConfig, error) {
+	// try to get compression level
+	compressionLevel, err := parseIntValue(r, "flac-compression-level", "compression level")
+	if err != nil {
+		return convert.FlacConfig{}, err
+	}
+
+	// try to get block size
+	blockSize, err := parseIntValue(r, "flac-block-size", "block size")
+	if err != nil {
+		return convert.FlacConfig{}, err
+	}
+
+	return convert.FlacConfig{CompressionLevel: compressionLevel, BlockSize: blockSize}, nil
+}
+
+func parseAlacConfig(r *http.Request) (convert.AlacConfig, error) {
+	// try to get sample format
+	sampleFormat, err := parseIntValue(r, "alac-sample-format", "sample format")
+	if err != nil {
+		return convert.AlacConfig{}, err
+	}
+
+	return convert.AlacConfig{SampleFormat: signal.BitDepth(sampleFormat)}, nil
+}
+
 // parseIntValue parses value of key provided in the html form.
 // Returns error if value is not provided or cannot be parsed as int.
 func parseIntValue(r *http.Request, key, name string) (int, error) {
@@ -213,6 +386,117 @@ const convertHTML = `
             document.getElementById('input-file-label').innerHTML = getFileName('input-file');
             displayClass('input-file-label', true);
             displayId('output-format', "");
+            if (document.getElementById('presets')) {
+                displayId('presets', "");
+            }
+            fetchPeaks();
+        }
+        function onPresetChange(el){
+            if (!el.value) {
+                displayId('output-format', "");
+                return;
+            }
+            displayClass('output-options', false);
+            displayId('output-format', "none");
+            displayId('submit', "");
+        }
+        var peaksData = null;
+        var trimDragHandle = null;
+        // trimStartMs/trimEndMs read the trim hidden inputs, defaulting to
+        // the full track range when the user hasn't dragged a handle yet.
+        // The inputs themselves are only ever written by onWaveformMouseMove,
+        // so an ordinary, non-trimmed conversion submits them blank.
+        function trimStartMs(){
+            var v = document.getElementById('trim-start').value;
+            return v === '' ? 0 : Number(v);
+        }
+        function trimEndMs(){
+            var v = document.getElementById('trim-end').value;
+            if (v !== '') {
+                return Number(v);
+            }
+            return peaksData ? peaksData.frames / peaksData.sample_rate * 1000 : 0;
+        }
+        function fetchPeaks(){
+            var fileInput = document.getElementById('input-file');
+            if (!fileInput.files.length) {
+                return;
+            }
+            var file = fileInput.files[0];
+            var format = file.name.split('.').pop();
+            if (format !== 'wav') {
+                displayId('waveform-unavailable', "");
+                return;
+            }
+            displayId('waveform-unavailable', "none");
+            var data = new FormData();
+            data.append('input-file', file);
+            data.append('format', format);
+            fetch('/peaks', {method: 'POST', body: data})
+                .then(function(resp){
+                    if (!resp.ok) {
+                        return resp.text().then(function(t){ throw new Error(t); });
+                    }
+                    return resp.json();
+                })
+                .then(function(json){
+                    peaksData = json;
+                    displayId('waveform-container', "");
+                    drawWaveform();
+                })
+                .catch(function(err){
+                    displayId('waveform-unavailable', "");
+                    document.getElementById('waveform-unavailable').innerHTML = 'Waveform preview unavailable: ' + err.message;
+                });
+        }
+        function drawWaveform(){
+            if (!peaksData) {
+                return;
+            }
+            var canvas = document.getElementById('waveform');
+            var ctx = canvas.getContext('2d');
+            var w = canvas.width, h = canvas.height, mid = h / 2;
+            var channels = peaksData.channels;
+            var buckets = peaksData.peaks.length / (channels * 2);
+            ctx.clearRect(0, 0, w, h);
+            ctx.strokeStyle = '#444';
+            for (var b = 0; b < buckets; b++) {
+                var min = peaksData.peaks[b * channels * 2];
+                var max = peaksData.peaks[b * channels * 2 + 1];
+                var x = b / buckets * w;
+                ctx.beginPath();
+                ctx.moveTo(x, mid - (max / 32768) * mid);
+                ctx.lineTo(x, mid - (min / 32768) * mid);
+                ctx.stroke();
+            }
+            var totalMs = peaksData.frames / peaksData.sample_rate * 1000;
+            var startX = trimStartMs() / totalMs * w;
+            var endX = trimEndMs() / totalMs * w;
+            ctx.fillStyle = 'rgba(68,68,68,0.2)';
+            ctx.fillRect(startX, 0, endX - startX, h);
+        }
+        function onWaveformMouseDown(e){
+            if (!peaksData) {
+                return;
+            }
+            var rect = e.target.getBoundingClientRect();
+            var x = e.clientX - rect.left;
+            var startX = trimStartMs() / (peaksData.frames / peaksData.sample_rate * 1000) * rect.width;
+            var endX = trimEndMs() / (peaksData.frames / peaksData.sample_rate * 1000) * rect.width;
+            trimDragHandle = Math.abs(x - startX) < Math.abs(x - endX) ? 'trim-start' : 'trim-end';
+        }
+        function onWaveformMouseMove(e){
+            if (!trimDragHandle || !peaksData) {
+                return;
+            }
+            var rect = e.target.getBoundingClientRect();
+            var x = Math.min(Math.max(e.clientX - rect.left, 0), rect.width);
+            var totalMs = peaksData.frames / peaksData.sample_rate * 1000;
+            document.getElementById(trimDragHandle).value = Math.round(x / rect.width * totalMs);
+            drawWaveform();
+        }
+        function onWaveformMouseUp(){
+            trimDragHandle = null;
         }
 		function onOutputFormatsClick(el){
         	displayClass('output-options', false);
@@ -232,11 +516,42 @@ const convertHTML = `
             }
         }
         function onSubmitClick(){
-            var fileName = getFileName('input-file')
-            var ext = fileName.split('.')[1];
             var convert = document.getElementById('convert');
-            convert.action = ext;
-            convert.submit();
+            var data = new FormData(convert);
+            document.getElementById('submit').disabled = true;
+            document.getElementById('convert-progress').value = 0;
+            displayId('convert-progress', "");
+            fetch('/jobs', {method: 'POST', body: data})
+                .then(function(resp){
+                    if (!resp.ok) {
+                        return resp.text().then(function(t){ throw new Error(t); });
+                    }
+                    return resp.json();
+                })
+                .then(function(json){ pollJob(json.id); })
+                .catch(onSubmitFailed);
+        }
+        function pollJob(id){
+            fetch('/jobs/' + id)
+                .then(function(resp){ return resp.json(); })
+                .then(function(job){
+                    document.getElementById('convert-progress').value = job.progress;
+                    if (job.status === 'done') {
+                        window.location = '/jobs/' + id + '/download';
+                        return;
+                    }
+                    if (job.status === 'failed') {
+                        onSubmitFailed(new Error(job.error));
+                        return;
+                    }
+                    setTimeout(function(){ pollJob(id); }, 500);
+                })
+                .catch(onSubmitFailed);
+        }
+        function onSubmitFailed(err){
+            document.getElementById('submit').disabled = false;
+            displayId('convert-progress', "none");
+            alert('Convert failed: ' + err.message);
         }
     </script> 
 </head>
@@ -247,8 +562,26 @@ const convertHTML = `
         <input id="input-file" type="file" name="input-file" accept="{{.Accept}}" style="display:none" onchange="onInputFileChange()"/>
         <label id="input-file-label" for="input-file">select file</label>
     </div>
+    <div id="waveform-container" style="display:none">
+        <canvas id="waveform" width="600" height="100" onmousedown="onWaveformMouseDown(event)" onmousemove="onWaveformMouseMove(event)" onmouseup="onWaveformMouseUp()"></canvas>
+        <input type="hidden" id="trim-start" name="trim-start">
+        <input type="hidden" id="trim-end" name="trim-end">
+    </div>
+    <div id="waveform-unavailable" style="display:none">waveform preview &amp; trimming is wav-only for now</div>
+    {{if .Presets}}
+    <div id="presets" style="display:none">
+        preset
+        <select id="preset-select" name="preset" onchange="onPresetChange(this)">
+            <option value="">custom</option>
+            {{range .Presets}}
+                <option value="{{ .Name }}">{{ .Name }} ({{ .Description }})</option>
+            {{end}}
+        </select>
+    <br>
+    </div>
+    {{end}}
     <div id="output-format" style="display:none">
-        output 
+        output
         {{range $key := .OutFormats}}
             <input type="radio" id="{{ $key }}" value="{{ $key }}" name="format" class="output-formats" onclick="onOutputFormatsClick(this)">
             <label for="{{ $key }}">{{ $key }}</label>
@@ -296,11 +629,40 @@ const convertHTML = `
                 [0-10]
                 <input type="text" name="mp3-quality" maxlength="2" size="3">
             </div>
-            <br>  
+            <br>
         </div>
     </div>
+    <div id="flac-options" class="output-options" style="display:none">
+        compression level
+        <select name="flac-compression-level">
+            <option hidden disabled selected value>select</option>
+            {{range $key, $value := .FlacOptions.CompressionLevels}}
+                <option value="{{ $key }}">{{ $value }}</option>
+            {{end}}
+        </select>
+        <br>
+        block size
+        <select name="flac-block-size">
+            <option hidden disabled selected value>select</option>
+            {{range $key, $value := .FlacOptions.BlockSizes}}
+                <option value="{{ $key }}">{{ $value }}</option>
+            {{end}}
+        </select>
+    <br>
+    </div>
+    <div id="alac-options" class="output-options" style="display:none">
+        sample format
+        <select name="alac-sample-format">
+            <option hidden disabled selected value>select</option>
+            {{range $key, $value := .AlacOptions.SampleFormats}}
+                <option value="{{ $key }}">{{ $value }}</option>
+            {{end}}
+        </select>
+    <br>
+    </div>
     </form>
-    <button id="submit" type="button" style="display:none" onclick="onSubmitClick()">convert</button> 
+    <button id="submit" type="button" style="display:none" onclick="onSubmitClick()">convert</button>
+    <progress id="convert-progress" max="1" value="0" style="display:none"></progress>
 </body>
 </html>
 `